@@ -6,11 +6,11 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/slack-go/slack"
-	"github.com/slack-go/slack/slackevents"
 	"github.com/slack-go/slack/socketmode"
 
 	"k8s.io/test-infra/pkg/flagutil"
@@ -18,10 +18,21 @@ import (
 	prowflagutil "k8s.io/test-infra/prow/flagutil"
 	"k8s.io/test-infra/prow/logrusutil"
 
+	commandrouter "github.com/vrutkovs/slack-jira-bot/pkg/slack/commands/router"
+	"github.com/vrutkovs/slack-jira-bot/pkg/slack/events/reaction"
 	eventrouter "github.com/vrutkovs/slack-jira-bot/pkg/slack/events/router"
 	interactionrouter "github.com/vrutkovs/slack-jira-bot/pkg/slack/interactions/router"
+	"github.com/vrutkovs/slack-jira-bot/pkg/slack/loghook"
+	"github.com/vrutkovs/slack-jira-bot/pkg/slack/modals/bug"
+	"github.com/vrutkovs/slack-jira-bot/pkg/slack/transport"
 
 	"github.com/vrutkovs/slack-jira-bot/pkg/jira"
+	"github.com/vrutkovs/slack-jira-bot/pkg/jira/dedupe"
+)
+
+const (
+	transportSocket = "socket"
+	transportHTTP   = "http"
 )
 
 type options struct {
@@ -35,7 +46,10 @@ type options struct {
 	slackTokenPath         string
 	slackAppTokenPath      string
 	slackSigningSecretPath string
+	slackErrorChannel      string
+	slackTransport         string
 	jiraProject            string
+	dupThreshold           float64
 }
 
 func (o *options) Validate() error {
@@ -60,6 +74,16 @@ func (o *options) Validate() error {
 		return fmt.Errorf("--jira-project is required")
 	}
 
+	if o.dupThreshold < 0 || o.dupThreshold > 1 {
+		return fmt.Errorf("--dup-threshold must be between 0 and 1")
+	}
+
+	switch o.slackTransport {
+	case transportSocket, transportHTTP:
+	default:
+		return fmt.Errorf("--slack-transport must be one of %q or %q", transportSocket, transportHTTP)
+	}
+
 	for _, group := range []flagutil.OptionGroup{&o.instrumentationOptions, &o.jiraOptions} {
 		if err := group.Validate(false); err != nil {
 			return err
@@ -83,7 +107,10 @@ func gatherOptions(fs *flag.FlagSet, args ...string) options {
 	fs.StringVar(&o.slackTokenPath, "slack-token-path", "", "Path to the file containing the Slack token to use.")
 	fs.StringVar(&o.slackAppTokenPath, "slack-app-token-path", "", "Path to the file containing the Slack app token to use.")
 	fs.StringVar(&o.slackSigningSecretPath, "slack-signing-secret-path", "", "Path to the file containing the Slack signing secret to use.")
+	fs.StringVar(&o.slackErrorChannel, "slack-error-channel", "", "If set, mirror Error, Fatal and Warn log entries to this Slack channel.")
+	fs.StringVar(&o.slackTransport, "slack-transport", transportSocket, "How to receive events from Slack: \"socket\" for Socket Mode or \"http\" to serve the Events API and Interactivity over HTTP.")
 	fs.StringVar(&o.jiraProject, "jira-project", "", "Jira project name.")
+	fs.Float64Var(&o.dupThreshold, "dup-threshold", dedupe.DefaultThreshold, "Similarity score above which an existing issue is surfaced as a likely duplicate before a new bug is filed.")
 
 	if err := fs.Parse(args); err != nil {
 		logrus.WithError(err).Fatal("Could not parse args.")
@@ -100,6 +127,7 @@ func main() {
 	}
 	level, _ := logrus.ParseLevel(o.logLevel)
 	logrus.SetLevel(level)
+	bug.DuplicateThreshold = o.dupThreshold
 
 	if err := secret.Add(o.slackTokenPath, o.slackAppTokenPath, o.slackSigningSecretPath); err != nil {
 		logrus.WithError(err).Fatal("Error starting secrets agent.")
@@ -111,6 +139,11 @@ func main() {
 	}
 
 	slackClient := slack.New(string(secret.GetSecret(o.slackTokenPath)), slack.OptionAppLevelToken(string(secret.GetSecret(o.slackAppTokenPath))))
+
+	if o.slackErrorChannel != "" {
+		logrus.AddHook(loghook.New(slackClient, o.slackErrorChannel))
+	}
+
 	issueFiler, err := jira.NewIssueFiler(slackClient, jiraClient.JiraClient(), o.jiraProject)
 	if err != nil {
 		logrus.WithError(err).Fatal("Could not initialize Jira issue filer.")
@@ -118,43 +151,59 @@ func main() {
 
 	logger := logrus.WithField("api", "events")
 
-	socketClient := socketmode.New(slackClient)
+	handler := func(ctx context.Context, event transport.IncomingEvent) {
+		entry := loghook.WithContext(logger, event.ID)
+		switch {
+		case event.EventsAPIEvent != nil:
+			event.Ack(nil)
+			eventrouter.ForEvents(slackClient).Handle(event.EventsAPIEvent, entry)
+		case event.Interaction != nil:
+			// reaction.FollowUp handles clicks on the "File as a bug" button
+			// reaction.Handler posts; that's a plain message action with no
+			// associated modal view, so it's tried before handing off to the
+			// per-view modal router.
+			if handled, payload, err := reaction.FollowUp(slackClient).Handle(event.Interaction, entry); handled {
+				if err != nil {
+					entry.WithError(err).Error("Error handling reaction follow-up.")
+				}
+				event.Ack(payload)
+				return
+			}
+			payload, err := interactionrouter.ForModals(issueFiler, slackClient).Handle(event.Interaction, entry)
+			if err != nil {
+				entry.WithError(err).Error("Error building interaction payload.")
+				event.Ack(nil)
+				return
+			}
+			event.Ack(payload)
+		case event.Command != nil:
+			event.Ack(nil)
+			if err := commandrouter.ForCommands(slackClient).Handle(event.Command, entry); err != nil {
+				entry.WithError(err).Error("Error handling slash command.")
+			}
+		}
+	}
+
+	var t transport.Transport
+	switch o.slackTransport {
+	case transportHTTP:
+		t = transport.NewHTTPTransport(o.port, string(secret.GetSecret(o.slackSigningSecretPath)), handler)
+	default:
+		t = transport.NewSocketModeTransport(socketmode.New(slackClient), handler)
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	go func(ctx context.Context, client *slack.Client, socketClient *socketmode.Client) {
-		for {
-			select {
-			case <-ctx.Done():
-				log.Println("Shutting down socketmode listener")
-				return
-			case event := <-socketClient.Events:
-				switch event.Type {
-				case socketmode.EventTypeEventsAPI:
-					eventData, ok := event.Data.(slackevents.EventsAPIEvent)
-					if !ok {
-						log.Printf("Could not type cast the event to the EventsAPIEvent: %v\n", event)
-						continue
-					}
-					socketClient.Ack(*event.Request)
-					eventrouter.ForEvents(slackClient).Handle(&eventData, logger)
-				case socketmode.EventTypeInteractive:
-					interactionData, ok := event.Data.(slack.InteractionCallback)
-					if !ok {
-						log.Printf("Could not type cast the event to the InteractionCallback: %v\n", event)
-						continue
-					}
-					payload, err := interactionrouter.ForModals(issueFiler, slackClient).Handle(&interactionData, logger)
-					if err != nil {
-						log.Printf("error building payload: %v\n", payload)
-						continue
-					}
-					socketClient.Ack(*event.Request, payload)
-				}
-			}
-		}
-	}(ctx, slackClient, socketClient)
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+	go func() {
+		<-sigCh
+		log.Println("Shutting down listener")
+		cancel()
+	}()
 
-	socketClient.Run()
+	if err := t.Run(ctx); err != nil {
+		logrus.WithError(err).Fatal("Transport exited with an error.")
+	}
 }
@@ -0,0 +1,104 @@
+// Package dedupe scores existing Jira issues against a new bug submission
+// by token Jaccard similarity, so a modal can offer "this looks like a
+// duplicate" before filing.
+package dedupe
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Issue is the subset of a Jira issue dedupe needs in order to score it
+// against a new submission.
+type Issue struct {
+	Key         string
+	Summary     string
+	Description string
+}
+
+// Searcher runs a JQL query and returns the matching issues. It is
+// satisfied by whatever Jira client the caller already has.
+type Searcher interface {
+	Search(jql string) ([]Issue, error)
+}
+
+// Candidate is an existing issue that looks like a duplicate of a new
+// submission, along with the similarity score that surfaced it.
+type Candidate struct {
+	Issue
+	Score float64
+}
+
+// DefaultThreshold is the similarity score above which a candidate is
+// surfaced as a likely duplicate.
+const DefaultThreshold = 0.6
+
+// maxCandidates bounds how many candidates are surfaced to the user.
+const maxCandidates = 5
+
+var stopwords = map[string]bool{
+	"a": true, "an": true, "the": true, "is": true, "are": true, "was": true,
+	"were": true, "be": true, "to": true, "of": true, "in": true, "on": true,
+	"and": true, "or": true, "it": true, "this": true, "that": true,
+	"with": true, "for": true, "at": true, "by": true, "as": true,
+}
+
+// Find searches for open issues in project and scores them against title
+// and symptom by token Jaccard similarity on normalized terms, returning
+// the candidates that score above threshold, highest score first, capped
+// at five.
+func Find(searcher Searcher, project, title, symptom string, threshold float64) ([]Candidate, error) {
+	issues, err := searcher.Search(fmt.Sprintf(`project = "%s" AND resolution = Unresolved ORDER BY created DESC`, project))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for duplicate issues: %w", err)
+	}
+
+	needle := tokenize(title + " " + symptom)
+	var candidates []Candidate
+	for _, issue := range issues {
+		score := jaccard(needle, tokenize(issue.Summary+" "+issue.Description))
+		if score > threshold {
+			candidates = append(candidates, Candidate{Issue: issue, Score: score})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Score > candidates[j].Score })
+	if len(candidates) > maxCandidates {
+		candidates = candidates[:maxCandidates]
+	}
+	return candidates, nil
+}
+
+// tokenize lowercases text, strips punctuation, and drops stopwords,
+// returning the remaining terms as a set.
+func tokenize(text string) map[string]bool {
+	tokens := map[string]bool{}
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		word = strings.Trim(word, ".,!?:;\"'()[]{}")
+		if word == "" || stopwords[word] {
+			continue
+		}
+		tokens[word] = true
+	}
+	return tokens
+}
+
+// jaccard returns the Jaccard similarity of two token sets: the size of
+// their intersection over the size of their union.
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	intersection := 0
+	for token := range a {
+		if b[token] {
+			intersection++
+		}
+	}
+	union := len(a) + len(b) - intersection
+	if union == 0 {
+		return 0
+	}
+	return float64(intersection) / float64(union)
+}
@@ -0,0 +1,83 @@
+package dedupe_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/vrutkovs/slack-jira-bot/pkg/jira"
+	"github.com/vrutkovs/slack-jira-bot/pkg/jira/dedupe"
+)
+
+func TestFind(t *testing.T) {
+	testCases := []struct {
+		name      string
+		issues    []dedupe.Issue
+		title     string
+		symptom   string
+		threshold float64
+		expected  []string
+	}{
+		{
+			name: "near-identical report surfaces as a duplicate",
+			issues: []dedupe.Issue{
+				{Key: "AICID-1", Summary: "Cluster install fails waiting for bootstrap", Description: "The bootstrap node never comes up during install"},
+				{Key: "AICID-2", Summary: "UI shows wrong cluster name", Description: "Cosmetic issue in the dashboard"},
+			},
+			title:     "Cluster install fails waiting for bootstrap",
+			symptom:   "The bootstrap node never comes up during install",
+			threshold: dedupe.DefaultThreshold,
+			expected:  []string{"AICID-1"},
+		},
+		{
+			name: "unrelated issues are not surfaced",
+			issues: []dedupe.Issue{
+				{Key: "AICID-3", Summary: "Something else entirely", Description: "Nothing in common here"},
+			},
+			title:     "Cluster install fails waiting for bootstrap",
+			symptom:   "The bootstrap node never comes up during install",
+			threshold: dedupe.DefaultThreshold,
+			expected:  nil,
+		},
+		{
+			name:      "no existing issues",
+			issues:    nil,
+			title:     "Cluster install fails",
+			symptom:   "Bootstrap never completes",
+			threshold: dedupe.DefaultThreshold,
+			expected:  nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filer := jira.NewFake("AICID")
+			filer.Issues = tc.issues
+
+			candidates, err := dedupe.Find(filer, "AICID", tc.title, tc.symptom, tc.threshold)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var keys []string
+			for _, candidate := range candidates {
+				keys = append(keys, candidate.Key)
+			}
+			if len(keys) != len(tc.expected) {
+				t.Fatalf("expected candidates %v, got %v", tc.expected, keys)
+			}
+			for i := range keys {
+				if keys[i] != tc.expected[i] {
+					t.Fatalf("expected candidates %v, got %v", tc.expected, keys)
+				}
+			}
+		})
+	}
+}
+
+func TestFindPropagatesSearchError(t *testing.T) {
+	filer := jira.NewFake("AICID")
+	filer.SearchErr = fmt.Errorf("boom")
+
+	if _, err := dedupe.Find(filer, "AICID", "title", "symptom", dedupe.DefaultThreshold); err == nil {
+		t.Fatal("expected an error but got none")
+	}
+}
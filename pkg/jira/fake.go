@@ -0,0 +1,82 @@
+package jira
+
+import (
+	"testing"
+
+	"github.com/slack-go/slack"
+
+	"github.com/vrutkovs/slack-jira-bot/pkg/jira/dedupe"
+)
+
+// FiledIssue is a bug FileIssue was asked to create.
+type FiledIssue struct {
+	Summary     string
+	Description string
+}
+
+// Fake is a test double for IssueFiler, satisfying the same Search and
+// AddWatcher capabilities the real filer does, so tests can exercise
+// duplicate-detection and watch-adding code paths without a real Jira
+// client.
+type Fake struct {
+	// Issues is returned by Search regardless of the JQL query, so tests
+	// can seed in exactly the candidates they want scored.
+	Issues []dedupe.Issue
+	// SearchErr, if set, is returned by Search instead of Issues.
+	SearchErr error
+
+	// Filed records every issue FileIssue was asked to create.
+	Filed []FiledIssue
+	// NextKey is returned as the key of the next issue FileIssue creates.
+	NextKey string
+	// FileErr, if set, is returned by FileIssue instead of filing.
+	FileErr error
+
+	// Watched records, by issue key, the Slack user ID of whoever
+	// AddWatcher was asked to add as a watcher.
+	Watched map[string]string
+	// WatchErr, if set, is returned by AddWatcher instead of watching.
+	WatchErr error
+
+	project string
+}
+
+// NewFake returns a Fake bound to project, mirroring how NewIssueFiler binds
+// a real IssueFiler to one.
+func NewFake(project string) *Fake {
+	return &Fake{project: project, Watched: map[string]string{}}
+}
+
+func (f *Fake) Project() string {
+	return f.project
+}
+
+func (f *Fake) Search(_ string) ([]dedupe.Issue, error) {
+	return f.Issues, f.SearchErr
+}
+
+func (f *Fake) FileIssue(summary, description string) (string, error) {
+	if f.FileErr != nil {
+		return "", f.FileErr
+	}
+	f.Filed = append(f.Filed, FiledIssue{Summary: summary, Description: description})
+	return f.NextKey, nil
+}
+
+func (f *Fake) AddWatcher(issueKey string, reporter *slack.User) error {
+	if f.WatchErr != nil {
+		return f.WatchErr
+	}
+	f.Watched[issueKey] = reporter.ID
+	return nil
+}
+
+// Validate fails t if FileIssue recorded a filed issue even though FileErr
+// was set. It does not check whether any issue should have been filed at
+// all.
+func (f *Fake) Validate(t *testing.T) {
+	t.Helper()
+	if f.FileErr != nil && len(f.Filed) > 0 {
+		t.Errorf("expected FileIssue to fail with %v, but it recorded %d filed issue(s)", f.FileErr, len(f.Filed))
+	}
+}
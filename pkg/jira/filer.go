@@ -0,0 +1,82 @@
+// Package jira is the production Jira client the bot files bugs through. It
+// backs the jira.IssueFiler interface pkg/slack/modals/bug depends on, plus
+// the optional dedupe.Searcher and watcher capabilities that package detects
+// at runtime.
+package jira
+
+import (
+	"fmt"
+
+	gojira "github.com/andygrunwald/go-jira"
+	"github.com/slack-go/slack"
+
+	"github.com/vrutkovs/slack-jira-bot/pkg/jira/dedupe"
+)
+
+// IssueFiler files bugs into a single Jira project, and supports searching
+// for and watching issues in that project.
+type IssueFiler struct {
+	slack   *slack.Client
+	jira    *gojira.Client
+	project string
+}
+
+// NewIssueFiler creates an IssueFiler that files into project using
+// jiraClient, resolving Slack users for Jira fields through slackClient.
+func NewIssueFiler(slackClient *slack.Client, jiraClient *gojira.Client, project string) (*IssueFiler, error) {
+	if project == "" {
+		return nil, fmt.Errorf("project must not be empty")
+	}
+	return &IssueFiler{slack: slackClient, jira: jiraClient, project: project}, nil
+}
+
+// Project returns the Jira project issues are filed into. duplicateCheckHandler
+// uses this to search for duplicates without needing the project threaded
+// through separately.
+func (f *IssueFiler) Project() string {
+	return f.project
+}
+
+// FileIssue creates a new bug-type issue from summary and description and
+// returns its key.
+func (f *IssueFiler) FileIssue(summary, description string) (string, error) {
+	issue := &gojira.Issue{
+		Fields: &gojira.IssueFields{
+			Project:     gojira.Project{Key: f.project},
+			Summary:     summary,
+			Description: description,
+			Type:        gojira.IssueType{Name: "Bug"},
+		},
+	}
+	created, _, err := f.jira.Issue.Create(issue)
+	if err != nil {
+		return "", fmt.Errorf("failed to file Jira issue: %w", err)
+	}
+	return created.Key, nil
+}
+
+// Search runs a JQL query and returns the subset of issue fields dedupe
+// needs to score them against a new submission.
+func (f *IssueFiler) Search(jql string) ([]dedupe.Issue, error) {
+	results, _, err := f.jira.Issue.Search(jql, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search Jira: %w", err)
+	}
+	issues := make([]dedupe.Issue, 0, len(results))
+	for _, issue := range results {
+		issues = append(issues, dedupe.Issue{Key: issue.Key, Summary: issue.Fields.Summary, Description: issue.Fields.Description})
+	}
+	return issues, nil
+}
+
+// AddWatcher adds reporter as a watcher on issueKey, used when a reporter
+// chooses to track an existing issue instead of filing a new one. reporter
+// is identified by Slack user ID, not name or email: a block_actions
+// payload's User carries only ID/Username/TeamID, never a populated
+// Profile, so keying off anything else would silently watch nobody.
+func (f *IssueFiler) AddWatcher(issueKey string, reporter *slack.User) error {
+	if _, err := f.jira.Issue.AddWatcher(issueKey, reporter.ID); err != nil {
+		return fmt.Errorf("failed to add %s as a watcher on %s: %w", reporter.ID, issueKey, err)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+// Package commands implements a registry of slash-command handlers for the
+// bot, dispatched from main.go for slash commands delivered by
+// pkg/slack/transport.
+package commands
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+// CommandHandler handles a single slash command invocation (e.g. the "bug"
+// in "/jira bug") and returns how the bot should respond.
+type CommandHandler func(cmd *slack.SlashCommand, args []string, logger *logrus.Entry) (Reply, error)
+
+// Reply describes how a CommandHandler wants to respond to a slash command:
+// either plain text or a follow-up modal.
+type Reply interface {
+	isReply()
+}
+
+// TextReply sends back a plain ephemeral text message.
+type TextReply struct {
+	Text string
+}
+
+func (TextReply) isReply() {}
+
+// ModalReply opens a follow-up modal for the user that invoked the command,
+// using the command's trigger ID.
+type ModalReply struct {
+	View slack.ModalViewRequest
+}
+
+func (ModalReply) isReply() {}
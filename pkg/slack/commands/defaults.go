@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+
+	"github.com/vrutkovs/slack-jira-bot/pkg/slack/modals/bug"
+)
+
+func init() {
+	RegisterCommand("bug", bugHandler)
+	RegisterCommand("search", searchHandler)
+	RegisterCommand("assign", assignHandler)
+}
+
+// bugHandler opens the same bug-filing modal used by the app_mention flow.
+func bugHandler(_ *slack.SlashCommand, _ []string, _ *logrus.Entry) (Reply, error) {
+	return ModalReply{View: bug.View(bug.Defaults{})}, nil
+}
+
+// searchHandler answers "/jira search PROJ-123". No Jira client is threaded
+// into this registry, so it looks nothing up and replies saying so.
+func searchHandler(cmd *slack.SlashCommand, args []string, logger *logrus.Entry) (Reply, error) {
+	if len(args) == 0 {
+		return TextReply{Text: "Usage: `/jira search <issue-key or terms>`"}, nil
+	}
+	logger.WithField("query", strings.Join(args, " ")).Debug("Jira search requested, but no Jira client is wired up.")
+	return TextReply{Text: "Jira search isn't wired up yet; nothing was looked up."}, nil
+}
+
+// assignHandler answers "/jira assign PROJ-123". No Jira client is threaded
+// into this registry, so it assigns nothing and replies saying so.
+func assignHandler(_ *slack.SlashCommand, args []string, _ *logrus.Entry) (Reply, error) {
+	if len(args) == 0 {
+		return TextReply{Text: "Usage: `/jira assign <issue-key>`"}, nil
+	}
+	return TextReply{Text: "Jira assignment isn't wired up yet; nothing was assigned."}, nil
+}
@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+// registry holds the handlers registered for each command name. Commands
+// are dispatched on the first word of the slash command's text, e.g.
+// "/jira bug" dispatches to the handler registered as "bug".
+var registry = &struct {
+	sync.RWMutex
+	handlers map[string]CommandHandler
+}{handlers: map[string]CommandHandler{}}
+
+// RegisterCommand registers handler to be invoked when the first word of a
+// slash command's text matches name. Registering a second handler under the
+// same name replaces the first, mirroring how later packages override
+// earlier registrations when they're loaded after them.
+func RegisterCommand(name string, handler CommandHandler) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.handlers[name] = handler
+}
+
+// Dispatch looks up the handler registered for the command's subcommand (the
+// first word of its text) and invokes it with the remaining words as args.
+func Dispatch(cmd *slack.SlashCommand, logger *logrus.Entry) (Reply, error) {
+	fields := strings.Fields(cmd.Text)
+	name := ""
+	var args []string
+	if len(fields) > 0 {
+		name = fields[0]
+		args = fields[1:]
+	}
+
+	registry.RLock()
+	handler, ok := registry.handlers[name]
+	registry.RUnlock()
+	if !ok {
+		return TextReply{Text: fmt.Sprintf("Unknown subcommand %q for %s. Try `bug`, `search`, or `assign`.", name, cmd.Command)}, nil
+	}
+
+	return handler(cmd, args, logger.WithField("subcommand", name))
+}
@@ -0,0 +1,54 @@
+// Package router wires the commands registry into the Slack API, turning a
+// dispatched Reply into the appropriate Slack call: opening a modal for a
+// ModalReply, or posting an ephemeral message for a TextReply. No reply type
+// posts a message with buttons; any other Reply is logged and ignored.
+package router
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+
+	"github.com/vrutkovs/slack-jira-bot/pkg/slack/commands"
+)
+
+// Handler dispatches an incoming slash command and carries out the Reply it
+// produces.
+type Handler interface {
+	Handle(cmd *slack.SlashCommand, logger *logrus.Entry) error
+}
+
+type handler struct {
+	client *slack.Client
+}
+
+// ForCommands returns a Handler that dispatches slash commands through the
+// commands registry and executes the resulting Reply against client.
+func ForCommands(client *slack.Client) Handler {
+	return &handler{client: client}
+}
+
+func (h *handler) Handle(cmd *slack.SlashCommand, logger *logrus.Entry) error {
+	reply, err := commands.Dispatch(cmd, logger)
+	if err != nil {
+		return fmt.Errorf("failed to dispatch command %q: %w", cmd.Command, err)
+	}
+	return h.execute(cmd, reply, logger)
+}
+
+func (h *handler) execute(cmd *slack.SlashCommand, reply commands.Reply, logger *logrus.Entry) error {
+	switch r := reply.(type) {
+	case commands.ModalReply:
+		if _, err := h.client.OpenView(cmd.TriggerID, r.View); err != nil {
+			return fmt.Errorf("failed to open modal for command %q: %w", cmd.Command, err)
+		}
+	case commands.TextReply:
+		if _, err := h.client.PostEphemeral(cmd.ChannelID, cmd.UserID, slack.MsgOptionText(r.Text, false)); err != nil {
+			return fmt.Errorf("failed to post ephemeral reply for command %q: %w", cmd.Command, err)
+		}
+	default:
+		logger.WithField("reply_type", fmt.Sprintf("%T", reply)).Warn("Unknown reply type from command handler.")
+	}
+	return nil
+}
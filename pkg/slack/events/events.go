@@ -0,0 +1,33 @@
+// Package events defines the Handler interface that event-specific
+// packages (mention, reaction, ...) implement, and a MultiHandler
+// combinator for composing them, mirroring interactions.MultiHandler.
+package events
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// Handler reacts to a single Events API callback.
+type Handler interface {
+	Handle(event *slackevents.EventsAPIEvent, logger *logrus.Entry)
+}
+
+// HandlerFunc adapts a function to a Handler.
+type HandlerFunc func(event *slackevents.EventsAPIEvent, logger *logrus.Entry)
+
+// Handle implements Handler.
+func (f HandlerFunc) Handle(event *slackevents.EventsAPIEvent, logger *logrus.Entry) {
+	f(event, logger)
+}
+
+// MultiHandler dispatches an event to every handler in order, letting
+// independent event-specific packages be combined without knowing about
+// each other.
+func MultiHandler(handlers ...Handler) Handler {
+	return HandlerFunc(func(event *slackevents.EventsAPIEvent, logger *logrus.Entry) {
+		for _, handler := range handlers {
+			handler.Handle(event, logger)
+		}
+	})
+}
@@ -0,0 +1,136 @@
+// Package reaction turns a reaction on a Slack message into a Jira bug
+// report, paralleling the mention package but triggered by reaction_added
+// events instead of app_mention ones.
+package reaction
+
+import (
+	"encoding/json"
+
+	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+
+	"github.com/openshift/ci-tools/pkg/slack/interactions"
+
+	"github.com/vrutkovs/slack-jira-bot/pkg/slack/events"
+	"github.com/vrutkovs/slack-jira-bot/pkg/slack/modals/bug"
+)
+
+// ActionFileBug is the action ID of the button posted by Handle, clicked to
+// open the pre-populated bug-filing modal.
+const ActionFileBug = "reaction_file_bug"
+
+// defaultEmoji are the reactions that prompt a user to file a bug, used
+// when Handler is not given an explicit list.
+var defaultEmoji = map[string]bool{"bug": true, "jira": true}
+
+// sourceMessage identifies the reacted-to message, round-tripped through
+// the button's value so FollowUp can re-fetch it once the user clicks
+// through.
+type sourceMessage struct {
+	Channel   string `json:"channel"`
+	Timestamp string `json:"timestamp"`
+}
+
+type handler struct {
+	client *slack.Client
+	emoji  map[string]bool
+}
+
+// Handler returns an events.Handler that reacts to reaction_added events:
+// when one of emoji (or bug/jira, if none are given) is added to a message,
+// it prompts the reacting user to file that message as a bug. Opening the
+// bug modal itself happens in FollowUp, since reaction_added carries no
+// trigger_id to open a view with.
+func Handler(client *slack.Client, emoji ...string) events.Handler {
+	allowed := defaultEmoji
+	if len(emoji) > 0 {
+		allowed = make(map[string]bool, len(emoji))
+		for _, e := range emoji {
+			allowed[e] = true
+		}
+	}
+	return &handler{client: client, emoji: allowed}
+}
+
+func (h *handler) Handle(event *slackevents.EventsAPIEvent, logger *logrus.Entry) {
+	reactionEvent, ok := event.InnerEvent.Data.(*slackevents.ReactionAddedEvent)
+	if !ok || !h.emoji[reactionEvent.Reaction] {
+		return
+	}
+	logger = logger.WithFields(logrus.Fields{"channel": reactionEvent.Item.Channel, "timestamp": reactionEvent.Item.Timestamp})
+
+	metadata, err := json.Marshal(sourceMessage{Channel: reactionEvent.Item.Channel, Timestamp: reactionEvent.Item.Timestamp})
+	if err != nil {
+		logger.WithError(err).Error("Failed to encode source message metadata.")
+		return
+	}
+
+	prompt := slack.MsgOptionBlocks(
+		&slack.SectionBlock{
+			Type: slack.MBTSection,
+			Text: &slack.TextBlockObject{Type: slack.MarkdownType, Text: "Want to track this as a Jira issue?"},
+		},
+		&slack.ActionBlock{
+			Type: slack.MBTAction,
+			Elements: &slack.BlockElements{ElementSet: []slack.BlockElement{
+				&slack.ButtonBlockElement{
+					Type:     slack.METButton,
+					ActionID: ActionFileBug,
+					Text:     &slack.TextBlockObject{Type: slack.PlainTextType, Text: "File as a bug"},
+					Value:    string(metadata),
+				},
+			}},
+		},
+	)
+	if _, err := h.client.PostEphemeral(reactionEvent.Item.Channel, reactionEvent.User, prompt); err != nil {
+		logger.WithError(err).Error("Failed to prompt reacting user to file a bug.")
+	}
+}
+
+// FollowUp handles clicks on the "File as a bug" button: it re-fetches the
+// reacted-to message and opens the bug modal pre-populated from it. The
+// view's CallbackID carries the source message as a bug.ReplyTarget, so
+// once the issue is filed a threaded reply links it back to the message
+// that prompted it.
+func FollowUp(client *slack.Client) interactions.PartialHandler {
+	return interactions.PartialHandlerFunc("reaction.file", func(callback *slack.InteractionCallback, logger *logrus.Entry) (bool, []byte, error) {
+		if len(callback.ActionCallback.BlockActions) == 0 || callback.ActionCallback.BlockActions[0].ActionID != ActionFileBug {
+			return false, nil, nil
+		}
+		action := callback.ActionCallback.BlockActions[0]
+
+		var source sourceMessage
+		if err := json.Unmarshal([]byte(action.Value), &source); err != nil {
+			logger.WithError(err).Error("Failed to decode source message metadata.")
+			return true, nil, err
+		}
+
+		history, err := client.GetConversationHistory(&slack.GetConversationHistoryParameters{
+			ChannelID: source.Channel,
+			Latest:    source.Timestamp,
+			Inclusive: true,
+			Limit:     1,
+		})
+		if err != nil || len(history.Messages) == 0 {
+			logger.WithError(err).Error("Failed to fetch reacted-to message.")
+			return true, nil, err
+		}
+		text := history.Messages[0].Text
+
+		replyTarget, err := json.Marshal(bug.ReplyTarget{Channel: source.Channel, Timestamp: source.Timestamp})
+		if err != nil {
+			logger.WithError(err).Error("Failed to encode reply target.")
+			return true, nil, err
+		}
+
+		view := bug.View(bug.Defaults{Title: text, Symptom: text})
+		view.PrivateMetadata = string(bug.Identifier)
+		view.CallbackID = string(replyTarget)
+		if _, err := client.OpenView(callback.TriggerID, view); err != nil {
+			logger.WithError(err).Error("Failed to open bug modal from reaction prompt.")
+			return true, nil, err
+		}
+		return true, nil, nil
+	})
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/vrutkovs/slack-jira-bot/pkg/slack/events"
 	"github.com/vrutkovs/slack-jira-bot/pkg/slack/events/mention"
+	"github.com/vrutkovs/slack-jira-bot/pkg/slack/events/reaction"
 )
 
 // ForEvents returns a Handler that appropriately routes
@@ -12,5 +13,6 @@ import (
 func ForEvents(client *slack.Client) events.Handler {
 	return events.MultiHandler(
 		mention.Handler(client),
+		reaction.Handler(client),
 	)
 }
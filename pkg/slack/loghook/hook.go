@@ -0,0 +1,123 @@
+// Package loghook provides a logrus.Hook that mirrors error-, fatal- and
+// warning-level log entries to a Slack channel, so operators can see Jira-filing failures
+// without tailing pod logs.
+package loghook
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+// FieldContextID is the logrus field key that carries the event or
+// interaction ID a log entry was produced while handling. Attach it with
+// WithContext so an error posted to Slack can be correlated back to the
+// Slack callback that triggered it.
+const FieldContextID = "context_id"
+
+// WithContext annotates entry with the ID of the event or interaction
+// currently being processed, so a Hook can surface it alongside the error.
+func WithContext(entry *logrus.Entry, id string) *logrus.Entry {
+	return entry.WithField(FieldContextID, id)
+}
+
+const defaultRateLimitWindow = 5 * time.Minute
+
+var levelColors = map[logrus.Level]string{
+	logrus.FatalLevel: "danger",
+	logrus.ErrorLevel: "danger",
+	logrus.WarnLevel:  "warning",
+}
+
+// Hook is a logrus.Hook that posts Error/Fatal (and Warn) entries to a
+// Slack channel as a rich attachment.
+type Hook struct {
+	client  *slack.Client
+	channel string
+	window  time.Duration
+
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+// Option configures a Hook.
+type Option func(*Hook)
+
+// WithRateLimitWindow overrides the default window within which duplicate
+// messages (same level and text) are dropped instead of re-posted.
+func WithRateLimitWindow(window time.Duration) Option {
+	return func(h *Hook) {
+		h.window = window
+	}
+}
+
+// New creates a Hook that posts to channel using client.
+func New(client *slack.Client, channel string, opts ...Option) *Hook {
+	h := &Hook{
+		client:  client,
+		channel: channel,
+		window:  defaultRateLimitWindow,
+		seen:    map[string]time.Time{},
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Levels implements logrus.Hook.
+func (h *Hook) Levels() []logrus.Level {
+	return []logrus.Level{logrus.FatalLevel, logrus.ErrorLevel, logrus.WarnLevel}
+}
+
+// Fire implements logrus.Hook.
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	if h.shouldDrop(entry) {
+		return nil
+	}
+
+	attachment := slack.Attachment{
+		Color: levelColors[entry.Level],
+		Text:  entry.Message,
+	}
+	for _, key := range sortedKeys(entry.Data) {
+		attachment.Fields = append(attachment.Fields, slack.AttachmentField{
+			Title: key,
+			Value: fmt.Sprintf("%v", entry.Data[key]),
+			Short: true,
+		})
+	}
+
+	_, _, err := h.client.PostMessage(h.channel, slack.MsgOptionAttachments(attachment))
+	return err
+}
+
+// shouldDrop reports whether entry is a duplicate of one already posted
+// within the rate-limit window, coalescing repeated failures into a single
+// Slack message instead of flooding the channel.
+func (h *Hook) shouldDrop(entry *logrus.Entry) bool {
+	key := fmt.Sprintf("%s|%s", entry.Level, entry.Message)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := entry.Time
+	if last, ok := h.seen[key]; ok && now.Sub(last) < h.window {
+		return true
+	}
+	h.seen[key] = now
+	return false
+}
+
+func sortedKeys(fields logrus.Fields) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
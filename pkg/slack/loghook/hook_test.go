@@ -0,0 +1,65 @@
+package loghook
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+func newCountingServer(t *testing.T) (*httptest.Server, *int32) {
+	t.Helper()
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&count, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "ts": "1"})
+	}))
+	t.Cleanup(server.Close)
+	return server, &count
+}
+
+func entryAt(when time.Time) *logrus.Entry {
+	return &logrus.Entry{Logger: logrus.New(), Level: logrus.ErrorLevel, Message: "Jira filing failed.", Time: when}
+}
+
+func TestHookFireCoalescesDuplicatesWithinWindow(t *testing.T) {
+	server, count := newCountingServer(t)
+	client := slack.New("test-token", slack.OptionAPIURL(server.URL+"/"))
+	hook := New(client, "C123", WithRateLimitWindow(time.Minute))
+
+	start := time.Unix(1000, 0)
+	if err := hook.Fire(entryAt(start)); err != nil {
+		t.Fatalf("unexpected error on first Fire: %v", err)
+	}
+	if err := hook.Fire(entryAt(start.Add(10 * time.Second))); err != nil {
+		t.Fatalf("unexpected error on second Fire: %v", err)
+	}
+
+	if got := atomic.LoadInt32(count); got != 1 {
+		t.Fatalf("expected 1 PostMessage call for duplicate entries inside the window, got %d", got)
+	}
+}
+
+func TestHookFirePostsAgainAfterWindow(t *testing.T) {
+	server, count := newCountingServer(t)
+	client := slack.New("test-token", slack.OptionAPIURL(server.URL+"/"))
+	hook := New(client, "C123", WithRateLimitWindow(time.Minute))
+
+	start := time.Unix(1000, 0)
+	if err := hook.Fire(entryAt(start)); err != nil {
+		t.Fatalf("unexpected error on first Fire: %v", err)
+	}
+	if err := hook.Fire(entryAt(start.Add(2 * time.Minute))); err != nil {
+		t.Fatalf("unexpected error on second Fire: %v", err)
+	}
+
+	if got := atomic.LoadInt32(count); got != 2 {
+		t.Fatalf("expected 2 PostMessage calls once the window has elapsed, got %d", got)
+	}
+}
@@ -0,0 +1,90 @@
+package bug
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+
+	"github.com/openshift/ci-tools/pkg/slack/modals"
+
+	"github.com/vrutkovs/slack-jira-bot/pkg/jira"
+	"github.com/vrutkovs/slack-jira-bot/pkg/jira/dedupe"
+)
+
+// fakeUpdater records the last view it was asked to push, standing in for
+// the real modals.ViewUpdater Slack provides in production.
+type fakeUpdater struct {
+	view slack.ModalViewRequest
+}
+
+func (u *fakeUpdater) UpdateView(view slack.ModalViewRequest, _, _, _ string) (*slack.ViewResponse, error) {
+	u.view = view
+	return &slack.ViewResponse{}, nil
+}
+
+func submissionCallback(title, symptom string) *slack.InteractionCallback {
+	return &slack.InteractionCallback{
+		View: slack.View{
+			State: &slack.ViewState{Values: map[string]map[string]slack.BlockAction{
+				modals.BlockIdTitle: {"title_input": {Value: title}},
+				blockIdSymptom:      {"symptom_input": {Value: symptom}},
+			}},
+		},
+	}
+}
+
+// TestDuplicateCheckHandlerAgainstFake exercises duplicateCheckHandler with
+// jira.Fake standing in for the real filer: Fake exposes the same Search,
+// Project and AddWatcher methods as jira.IssueFiler, so the handler takes
+// its duplicate-search path.
+func TestDuplicateCheckHandlerAgainstFake(t *testing.T) {
+	filer := jira.NewFake("AICID")
+	filer.Issues = []dedupe.Issue{
+		{Key: "AICID-1", Summary: "Cluster install fails waiting for bootstrap", Description: "The bootstrap node never comes up during install"},
+	}
+
+	callback := submissionCallback("Cluster install fails waiting for bootstrap", "The bootstrap node never comes up during install")
+	handled, payload, err := duplicateCheckHandler(filer).Handle(callback, logrus.NewEntry(logrus.New()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !handled {
+		t.Fatal("expected the duplicate picker to intercept the submission, but it fell through to filing")
+	}
+
+	var response slack.ViewSubmissionResponse
+	if err := json.Unmarshal(payload, &response); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if response.ResponseAction != slack.RAPush {
+		t.Fatalf("expected a pushed duplicate picker, got response_action %q", response.ResponseAction)
+	}
+}
+
+// TestDuplicateActionHandlerAddsWatcherAgainstFake exercises the "This is a
+// duplicate of X" button against jira.Fake, confirming the handler reaches
+// AddWatcher on a filer that provides it.
+func TestDuplicateActionHandlerAddsWatcherAgainstFake(t *testing.T) {
+	filer := jira.NewFake("AICID")
+	updater := &fakeUpdater{}
+	reporter := slack.User{ID: "U123"}
+
+	callback := &slack.InteractionCallback{
+		User: reporter,
+		ActionCallback: slack.ActionCallbacks{
+			BlockActions: []*slack.BlockAction{{ActionID: duplicateWatchActionPrefix + "AICID-1"}},
+		},
+	}
+	handled, _, err := duplicateActionHandler(filer, updater).Handle(callback, logrus.NewEntry(logrus.New()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !handled {
+		t.Fatal("expected the watch button click to be handled")
+	}
+	if got := filer.Watched["AICID-1"]; got != reporter.ID {
+		t.Fatalf("expected %s to be added as a watcher on AICID-1, got %q", reporter.ID, got)
+	}
+}
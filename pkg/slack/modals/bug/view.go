@@ -2,6 +2,8 @@ package bug
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 	"text/template"
 
 	"github.com/sirupsen/logrus"
@@ -10,6 +12,8 @@ import (
 	"github.com/openshift/ci-tools/pkg/jira"
 	"github.com/openshift/ci-tools/pkg/slack/interactions"
 	"github.com/openshift/ci-tools/pkg/slack/modals"
+
+	"github.com/vrutkovs/slack-jira-bot/pkg/jira/dedupe"
 )
 
 // Identifier is the view identifier for this modal
@@ -22,6 +26,7 @@ const (
 	blockIdExpected     = "expected"
 	blockIdImpact       = "impact"
 	blockIdReproduction = "reproduction"
+	blockIdConfirmNew   = "confirm-new"
 
 	componentAI    = "Assisted Installer"
 	componentUI    = "MGMT UI"
@@ -29,8 +34,44 @@ const (
 	componentOther = "Other"
 )
 
+// confirmNewOption is the single checkbox a reporter ticks to file a new
+// issue after the duplicate picker has already shown them similar ones. It
+// lives in its own block, never in issueParameters().Fields, so it can never
+// leak into the filed Jira issue the way a marker embedded in the title
+// would.
+var confirmNewOption = &slack.OptionBlockObject{
+	Value: "confirmed",
+	Text:  &slack.TextBlockObject{Type: slack.PlainTextType, Text: "File as a new issue even though similar issues were found"},
+}
+
+func confirmNewInitialOptions(checked bool) []*slack.OptionBlockObject {
+	if !checked {
+		return nil
+	}
+	return []*slack.OptionBlockObject{confirmNewOption}
+}
+
+// Defaults pre-populates the bug form, e.g. when it's opened from a
+// reacted-to Slack message rather than from scratch, or when the duplicate
+// picker restores it after the reporter chooses to file anyway.
+type Defaults struct {
+	Title   string
+	Symptom string
+
+	// SkipDuplicateCheck pre-checks the "file anyway" confirmation, letting
+	// duplicateCheckHandler let this submission through without searching
+	// again.
+	SkipDuplicateCheck bool
+
+	// ReplyCallbackID carries a view's CallbackID (a JSON-encoded
+	// ReplyTarget, set by callers like the reaction package) forward across
+	// a trip through the duplicate picker, so "file anyway" can restore it
+	// onto the resubmitted form.
+	ReplyCallbackID string `json:"replyCallbackID,omitempty"`
+}
+
 // View is the modal view for submitting a new bug to Jira
-func View() slack.ModalViewRequest {
+func View(defaults Defaults) slack.ModalViewRequest {
 	return slack.ModalViewRequest{
 		Type:            slack.VTModal,
 		PrivateMetadata: string(Identifier),
@@ -49,7 +90,7 @@ func View() slack.ModalViewRequest {
 				Type:    slack.MBTInput,
 				BlockID: modals.BlockIdTitle,
 				Label:   &slack.TextBlockObject{Type: slack.PlainTextType, Text: "Provide a title for this bug:"},
-				Element: &slack.PlainTextInputBlockElement{Type: slack.METPlainTextInput},
+				Element: &slack.PlainTextInputBlockElement{Type: slack.METPlainTextInput, InitialValue: defaults.Title},
 			},
 			&slack.InputBlock{
 				Type:    slack.MBTInput,
@@ -80,7 +121,7 @@ func View() slack.ModalViewRequest {
 				Type:    slack.MBTInput,
 				BlockID: blockIdSymptom,
 				Label:   &slack.TextBlockObject{Type: slack.PlainTextType, Text: "What incorrect behavior did you notice?"},
-				Element: &slack.PlainTextInputBlockElement{Type: slack.METPlainTextInput, Multiline: true},
+				Element: &slack.PlainTextInputBlockElement{Type: slack.METPlainTextInput, Multiline: true, InitialValue: defaults.Symptom},
 			},
 			&slack.InputBlock{
 				Type:    slack.MBTInput,
@@ -88,6 +129,17 @@ func View() slack.ModalViewRequest {
 				Label:   &slack.TextBlockObject{Type: slack.PlainTextType, Text: "What behavior did you expect instead?"},
 				Element: &slack.PlainTextInputBlockElement{Type: slack.METPlainTextInput, Multiline: true},
 			},
+			&slack.InputBlock{
+				Type:     slack.MBTInput,
+				BlockID:  blockIdConfirmNew,
+				Optional: true,
+				Label:    &slack.TextBlockObject{Type: slack.PlainTextType, Text: "Duplicate check"},
+				Element: &slack.CheckboxGroupsBlockElement{
+					Type:           slack.METCheckboxGroups,
+					Options:        []*slack.OptionBlockObject{confirmNewOption},
+					InitialOptions: confirmNewInitialOptions(defaults.SkipDuplicateCheck),
+				},
+			},
 		}},
 	}
 }
@@ -124,6 +176,224 @@ func validateSubmissionHandler() interactions.PartialHandler {
 	})
 }
 
+// DuplicateThreshold is the similarity score above which an existing issue
+// is surfaced as a likely duplicate before a new one is filed. It defaults
+// to dedupe.DefaultThreshold; main wires --dup-threshold into it at startup,
+// before any requests are served.
+var DuplicateThreshold = dedupe.DefaultThreshold
+
+const (
+	duplicateWatchActionPrefix = "bug.duplicate.watch:"
+	duplicateFileAnywayAction  = "bug.duplicate.file-anyway"
+)
+
+// projectSource is an optional capability a jira.IssueFiler may implement,
+// mirroring how dedupe.Searcher and watcher are detected: the production
+// filer is bound to a single project at construction time, so duplicate
+// search reads it from the filer instead of Register taking a project
+// argument that every caller would need to keep in sync separately.
+type projectSource interface {
+	Project() string
+}
+
+// duplicateCheckHandler searches filer's project for open issues that look
+// like this submission before a new issue is filed. If one or more
+// candidates score above DuplicateThreshold, it pushes a follow-up modal
+// listing them so the reporter can watch an existing issue instead of
+// filing a new one, or confirm they want to file anyway.
+func duplicateCheckHandler(filer jira.IssueFiler) interactions.PartialHandler {
+	return interactions.PartialHandlerFunc(string(Identifier)+".dedupe", func(callback *slack.InteractionCallback, logger *logrus.Entry) (bool, []byte, error) {
+		if checkboxChecked(callback, blockIdConfirmNew) {
+			return false, nil, nil
+		}
+
+		searcher, ok := filer.(dedupe.Searcher)
+		if !ok {
+			logger.Debug("Jira client does not support duplicate search; filing normally.")
+			return false, nil, nil
+		}
+		projecter, ok := filer.(projectSource)
+		if !ok {
+			logger.Debug("Jira client does not expose its project; filing normally.")
+			return false, nil, nil
+		}
+
+		title := fieldValue(callback, modals.BlockIdTitle)
+		symptom := fieldValue(callback, blockIdSymptom)
+		candidates, err := dedupe.Find(searcher, projecter.Project(), title, symptom, DuplicateThreshold)
+		if err != nil {
+			logger.WithError(err).Warn("Failed to search for duplicate issues; filing normally.")
+			return false, nil, nil
+		}
+		if len(candidates) == 0 {
+			return false, nil, nil
+		}
+
+		logger.WithField("candidates", len(candidates)).Debug("Detected likely duplicate submission.")
+		view := duplicatePickerView(candidates, Defaults{Title: title, Symptom: symptom, ReplyCallbackID: callback.View.CallbackID})
+		response, err := json.Marshal(&slack.ViewSubmissionResponse{
+			ResponseAction: slack.RAPush,
+			View:           &view,
+		})
+		if err != nil {
+			logger.WithError(err).Error("Failed to marshal view submission response.")
+			return true, nil, err
+		}
+		return true, response, nil
+	})
+}
+
+// duplicatePickerView lists candidates as buttons offering to watch the
+// existing issue, plus a button to file anyway. original carries the
+// reporter's in-progress title and symptom (and the view's CallbackID) so
+// those can be restored if they choose to file anyway; the other fields of
+// the form are not carried over and come back empty.
+func duplicatePickerView(candidates []dedupe.Candidate, original Defaults) slack.ModalViewRequest {
+	blocks := []slack.Block{
+		&slack.SectionBlock{
+			Type: slack.MBTSection,
+			Text: &slack.TextBlockObject{
+				Type: slack.PlainTextType,
+				Text: "These existing issues look similar to what you're reporting. Watch one instead, or file a new issue anyway.",
+			},
+		},
+	}
+	for _, candidate := range candidates {
+		blocks = append(blocks, &slack.SectionBlock{
+			Type: slack.MBTSection,
+			Text: &slack.TextBlockObject{Type: slack.MarkdownType, Text: fmt.Sprintf("*%s*\n%s", candidate.Key, candidate.Summary)},
+			Accessory: &slack.Accessory{
+				ButtonElement: &slack.ButtonBlockElement{
+					Type:     slack.METButton,
+					ActionID: duplicateWatchActionPrefix + candidate.Key,
+					Text:     &slack.TextBlockObject{Type: slack.PlainTextType, Text: fmt.Sprintf("This is a duplicate of %s", candidate.Key)},
+					Value:    candidate.Key,
+				},
+			},
+		})
+	}
+	blocks = append(blocks,
+		&slack.DividerBlock{Type: slack.MBTDivider},
+		&slack.ActionBlock{
+			Type: slack.MBTAction,
+			Elements: &slack.BlockElements{ElementSet: []slack.BlockElement{
+				&slack.ButtonBlockElement{
+					Type:     slack.METButton,
+					ActionID: duplicateFileAnywayAction,
+					Text:     &slack.TextBlockObject{Type: slack.PlainTextType, Text: "File anyway"},
+					Value:    encodeOriginal(original),
+				},
+			}},
+		},
+	)
+	return slack.ModalViewRequest{
+		Type:            slack.VTModal,
+		PrivateMetadata: string(Identifier),
+		Title:           &slack.TextBlockObject{Type: slack.PlainTextType, Text: "Possible Duplicate"},
+		Close:           &slack.TextBlockObject{Type: slack.PlainTextType, Text: "Cancel"},
+		Blocks:          slack.Blocks{BlockSet: blocks},
+	}
+}
+
+// watcher is an optional capability a jira.IssueFiler may implement,
+// mirroring how dedupe.Searcher is detected. When present, the duplicate
+// picker uses it to add the reporter to an existing issue instead of filing
+// a new one.
+type watcher interface {
+	AddWatcher(issueKey string, reporter *slack.User) error
+}
+
+// duplicateActionHandler handles clicks on the modal duplicateCheckHandler
+// pushes: watching an existing issue instead of filing, or restoring the
+// original form so the reporter can file anyway.
+func duplicateActionHandler(filer jira.IssueFiler, updater modals.ViewUpdater) interactions.PartialHandler {
+	return interactions.PartialHandlerFunc(string(Identifier)+".dedupe.action", func(callback *slack.InteractionCallback, logger *logrus.Entry) (bool, []byte, error) {
+		for _, action := range callback.ActionCallback.BlockActions {
+			switch {
+			case action.ActionID == duplicateFileAnywayAction:
+				original, err := decodeOriginal(action.Value)
+				if err != nil {
+					logger.WithError(err).Error("Failed to decode original submission.")
+					return true, nil, err
+				}
+				original.SkipDuplicateCheck = true
+				view := View(original)
+				view.CallbackID = original.ReplyCallbackID
+				if _, err := updater.UpdateView(view, "", callback.View.Hash, callback.View.ID); err != nil {
+					return true, nil, fmt.Errorf("failed to restore bug form: %w", err)
+				}
+				return true, nil, nil
+			case strings.HasPrefix(action.ActionID, duplicateWatchActionPrefix):
+				key := strings.TrimPrefix(action.ActionID, duplicateWatchActionPrefix)
+				view := confirmationView(fmt.Sprintf("You've been added as a watcher on %s.", key))
+				if w, ok := filer.(watcher); ok {
+					if err := w.AddWatcher(key, &callback.User); err != nil {
+						logger.WithError(err).WithField("issue", key).Warn("Failed to add reporter as a watcher.")
+						view = confirmationView(fmt.Sprintf("Couldn't add you as a watcher on %s automatically; watch it directly in Jira.", key))
+					}
+				} else {
+					logger.Debug("Jira client does not support adding watchers.")
+					view = confirmationView(fmt.Sprintf("Couldn't add you as a watcher on %s automatically; watch it directly in Jira.", key))
+				}
+				if _, err := updater.UpdateView(view, "", callback.View.Hash, callback.View.ID); err != nil {
+					return true, nil, fmt.Errorf("failed to confirm watch for %s: %w", key, err)
+				}
+				return true, nil, nil
+			}
+		}
+		return false, nil, nil
+	})
+}
+
+func confirmationView(text string) slack.ModalViewRequest {
+	return slack.ModalViewRequest{
+		Type:            slack.VTModal,
+		PrivateMetadata: string(Identifier),
+		Title:           &slack.TextBlockObject{Type: slack.PlainTextType, Text: "File a Bug"},
+		Close:           &slack.TextBlockObject{Type: slack.PlainTextType, Text: "Done"},
+		Blocks: slack.Blocks{BlockSet: []slack.Block{
+			&slack.SectionBlock{
+				Type: slack.MBTSection,
+				Text: &slack.TextBlockObject{Type: slack.PlainTextType, Text: text},
+			},
+		}},
+	}
+}
+
+func encodeOriginal(defaults Defaults) string {
+	raw, err := json.Marshal(defaults)
+	if err != nil {
+		return ""
+	}
+	return string(raw)
+}
+
+func decodeOriginal(value string) (Defaults, error) {
+	var defaults Defaults
+	if err := json.Unmarshal([]byte(value), &defaults); err != nil {
+		return Defaults{}, fmt.Errorf("failed to unmarshal original submission: %w", err)
+	}
+	return defaults, nil
+}
+
+// fieldValue returns the first plain-text input value submitted for
+// blockID, or the empty string if none was provided.
+func fieldValue(callback *slack.InteractionCallback, blockID string) string {
+	for _, action := range callback.View.State.Values[blockID] {
+		return action.Value
+	}
+	return ""
+}
+
+// checkboxChecked reports whether any option in the checkbox group
+// submitted for blockID is selected.
+func checkboxChecked(callback *slack.InteractionCallback, blockID string) bool {
+	for _, action := range callback.View.State.Values[blockID] {
+		return len(action.SelectedOptions) > 0
+	}
+	return false
+}
+
 func issueParameters() modals.JiraIssueParameters {
 	return modals.JiraIssueParameters{
 		Id:        Identifier,
@@ -140,17 +410,79 @@ h3. Category
 	}
 }
 
+// ReplyTarget identifies a Slack message that prompted a bug submission.
+// Callers that open the bug view in response to a message (e.g. the
+// reaction package) JSON-encode one into the view's CallbackID; once the
+// issue is filed, processSubmissionHandler posts a threaded reply to that
+// message linking the new Jira issue.
+type ReplyTarget struct {
+	Channel   string `json:"channel"`
+	Timestamp string `json:"timestamp"`
+}
+
+func decodeReplyTarget(callbackID string) (ReplyTarget, bool) {
+	if callbackID == "" {
+		return ReplyTarget{}, false
+	}
+	var target ReplyTarget
+	if err := json.Unmarshal([]byte(callbackID), &target); err != nil {
+		return ReplyTarget{}, false
+	}
+	return target, target.Channel != "" && target.Timestamp != ""
+}
+
+// keyCapturingFiler wraps a jira.IssueFiler so replyingUpdater can learn the
+// key of the issue modals.ToJiraIssue just filed directly from the call
+// that created it, instead of recovering it by pattern-matching whatever
+// confirmation text the updated view happens to render.
+type keyCapturingFiler struct {
+	jira.IssueFiler
+	lastKey string
+}
+
+func (f *keyCapturingFiler) FileIssue(summary, description string) (string, error) {
+	key, err := f.IssueFiler.FileIssue(summary, description)
+	f.lastKey = key
+	return key, err
+}
+
+// replyingUpdater decorates a modals.ViewUpdater so that, once filer has
+// filed an issue for a view carrying a ReplyTarget in its CallbackID, it
+// also posts a threaded reply linking the issue back to that target. It's
+// safe to share across concurrent submissions: the target is read fresh
+// from each call's view, and filer.lastKey is only ever set by the same
+// submission's FileIssue call before UpdateView runs.
+type replyingUpdater struct {
+	modals.ViewUpdater
+	client *slack.Client
+	filer  *keyCapturingFiler
+}
+
+func (u *replyingUpdater) UpdateView(view slack.ModalViewRequest, externalID, hash, viewID string) (*slack.ViewResponse, error) {
+	resp, err := u.ViewUpdater.UpdateView(view, externalID, hash, viewID)
+	if target, ok := decodeReplyTarget(view.CallbackID); ok && u.filer.lastKey != "" {
+		key := u.filer.lastKey
+		if _, _, postErr := u.client.PostMessage(target.Channel, slack.MsgOptionTS(target.Timestamp), slack.MsgOptionText(fmt.Sprintf("Filed as %s", key), false)); postErr != nil {
+			logrus.WithError(postErr).WithField("issue", key).Error("Failed to post threaded reply linking filed issue.")
+		}
+	}
+	return resp, err
+}
+
 // processSubmissionHandler files a Jira issue for this form
-func processSubmissionHandler(filer jira.IssueFiler, updater modals.ViewUpdater) interactions.PartialHandler {
-	return interactions.PartialFromHandler(modals.ToJiraIssue(issueParameters(), filer, updater))
+func processSubmissionHandler(filer jira.IssueFiler, client *slack.Client) interactions.PartialHandler {
+	capturing := &keyCapturingFiler{IssueFiler: filer}
+	return interactions.PartialFromHandler(modals.ToJiraIssue(issueParameters(), capturing, &replyingUpdater{ViewUpdater: client, client: client, filer: capturing}))
 }
 
 // Register creates a registration entry for the bug form
 func Register(filer jira.IssueFiler, client *slack.Client) *modals.FlowWithViewAndFollowUps {
-	return modals.ForView(Identifier, View()).WithFollowUps(map[slack.InteractionType]interactions.Handler{
+	return modals.ForView(Identifier, View(Defaults{})).WithFollowUps(map[slack.InteractionType]interactions.Handler{
 		slack.InteractionTypeViewSubmission: interactions.MultiHandler(
 			validateSubmissionHandler(),
+			duplicateCheckHandler(filer),
 			processSubmissionHandler(filer, client),
 		),
+		slack.InteractionTypeBlockActions: duplicateActionHandler(filer, client),
 	})
 }
@@ -0,0 +1,200 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// HTTPTransport delivers events via the classic Events API and
+// Interactivity HTTP endpoints, verified with the app's signing secret, so
+// the bot can run behind an ingress without Socket Mode.
+type HTTPTransport struct {
+	port          int
+	signingSecret string
+	handler       Handler
+}
+
+// NewHTTPTransport creates a Transport that serves /slack/events,
+// /slack/interactions and /slack/commands on port, verifying every request
+// with signingSecret.
+func NewHTTPTransport(port int, signingSecret string, handler Handler) *HTTPTransport {
+	return &HTTPTransport{port: port, signingSecret: signingSecret, handler: handler}
+}
+
+// Run starts the HTTP server. It blocks until ctx is cancelled.
+func (t *HTTPTransport) Run(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/slack/events", t.handleEvents)
+	mux.HandleFunc("/slack/interactions", t.handleInteractions)
+	mux.HandleFunc("/slack/commands", t.handleCommands)
+
+	server := &http.Server{Addr: fmt.Sprintf(":%d", t.port), Handler: mux}
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+func (t *HTTPTransport) verify(header http.Header, body []byte) error {
+	verifier, err := slack.NewSecretsVerifier(header, t.signingSecret)
+	if err != nil {
+		return err
+	}
+	if _, err := verifier.Write(body); err != nil {
+		return err
+	}
+	return verifier.Ensure()
+}
+
+func (t *HTTPTransport) handleEvents(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := t.verify(r.Header, body); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	event, err := slackevents.ParseEvent(body, slackevents.OptionNoVerifyToken())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if event.Type == slackevents.URLVerification {
+		var challenge slackevents.ChallengeResponse
+		if err := json.Unmarshal(body, &challenge); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(challenge.Challenge))
+		return
+	}
+
+	// Ack immediately and hand off to the handler in the background: Slack
+	// expects the 200 within ~3s, and WriteHeader alone doesn't put bytes on
+	// the wire until this handler returns, so running t.handler inline here
+	// would hold the ack hostage to however long processing takes. Use a
+	// detached context since r.Context() is cancelled the moment this
+	// handler returns, which would happen before the goroutine runs.
+	w.WriteHeader(http.StatusOK)
+	go t.handler(context.Background(), IncomingEvent{
+		ID:             requestID(r),
+		EventsAPIEvent: &event,
+		Ack:            func(interface{}) {},
+	})
+}
+
+func (t *HTTPTransport) handleInteractions(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := t.verify(r.Header, body); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var callback slack.InteractionCallback
+	if err := json.Unmarshal([]byte(values.Get("payload")), &callback); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Interaction handling may produce a response_action payload that must
+	// be written synchronously as the HTTP response, so block until Ack is
+	// called instead of acking eagerly like handleEvents does.
+	done := make(chan interface{}, 1)
+	t.handler(r.Context(), IncomingEvent{
+		ID:          requestID(r),
+		Interaction: &callback,
+		Ack: func(payload interface{}) {
+			done <- payload
+		},
+	})
+	response := <-done
+
+	if response != nil {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleCommands parses a form-encoded slash command payload and populates
+// IncomingEvent.Command, mirroring how SocketModeTransport populates it for
+// socketmode.EventTypeSlashCommand so both transports support slash
+// commands. Slash command acks are a plain 200, so the ack is written
+// eagerly and the handler runs in the background, like handleEvents does,
+// with no response_action to wait for.
+func (t *HTTPTransport) handleCommands(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := t.verify(r.Header, body); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	cmd := slack.SlashCommand{
+		Token:          values.Get("token"),
+		TeamID:         values.Get("team_id"),
+		TeamDomain:     values.Get("team_domain"),
+		EnterpriseID:   values.Get("enterprise_id"),
+		EnterpriseName: values.Get("enterprise_name"),
+		ChannelID:      values.Get("channel_id"),
+		ChannelName:    values.Get("channel_name"),
+		UserID:         values.Get("user_id"),
+		UserName:       values.Get("user_name"),
+		Command:        values.Get("command"),
+		Text:           values.Get("text"),
+		ResponseURL:    values.Get("response_url"),
+		TriggerID:      values.Get("trigger_id"),
+		APIAppID:       values.Get("api_app_id"),
+	}
+
+	w.WriteHeader(http.StatusOK)
+	go t.handler(context.Background(), IncomingEvent{
+		ID:      requestID(r),
+		Command: &cmd,
+		Ack:     func(interface{}) {},
+	})
+}
+
+// requestID derives a correlation ID for a request from Slack's own replay
+// timestamp header and the caller's address, since HTTP deliveries have no
+// envelope ID the way Socket Mode events do.
+func requestID(r *http.Request) string {
+	return fmt.Sprintf("%s-%s", r.Header.Get("X-Slack-Request-Timestamp"), r.RemoteAddr)
+}
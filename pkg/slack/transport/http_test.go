@@ -0,0 +1,121 @@
+package transport
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSigningSecret = "super-secret"
+
+// sign computes a Slack v0 request signature over body the way Slack's own
+// signing-secret verification expects, so tests can build requests that
+// pass t.verify.
+func sign(secret, timestamp, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":" + body))
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// currentTimestamp returns the current Unix time as Slack sends it in
+// X-Slack-Request-Timestamp; the signing-secret verifier rejects timestamps
+// more than five minutes away from now.
+func currentTimestamp() string {
+	return strconv.FormatInt(time.Now().Unix(), 10)
+}
+
+func signedRequest(t *testing.T, timestamp, body string) *http.Request {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/slack/events", strings.NewReader(body))
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", sign(testSigningSecret, timestamp, body))
+	return req
+}
+
+func TestHandleEventsRejectsBadSignature(t *testing.T) {
+	tr := NewHTTPTransport(0, testSigningSecret, func(context.Context, IncomingEvent) {})
+
+	req := signedRequest(t, currentTimestamp(), `{"type":"url_verification","challenge":"abc"}`)
+	req.Header.Set("X-Slack-Signature", "v0=0000000000000000000000000000000000000000000000000000000000000000")
+
+	rec := httptest.NewRecorder()
+	tr.handleEvents(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected %d for a bad signature, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
+
+func TestHandleEventsRejectsMissingSignature(t *testing.T) {
+	tr := NewHTTPTransport(0, testSigningSecret, func(context.Context, IncomingEvent) {})
+
+	body := `{"type":"url_verification","challenge":"abc"}`
+	req := httptest.NewRequest(http.MethodPost, "/slack/events", strings.NewReader(body))
+
+	rec := httptest.NewRecorder()
+	tr.handleEvents(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected %d for a missing signature, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
+
+func TestHandleCommandsRejectsBadSignature(t *testing.T) {
+	tr := NewHTTPTransport(0, testSigningSecret, func(context.Context, IncomingEvent) {
+		t.Fatal("handler should not be invoked when the signature is bad")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/slack/commands", strings.NewReader("command=/jira&text=bug"))
+	req.Header.Set("X-Slack-Request-Timestamp", currentTimestamp())
+	req.Header.Set("X-Slack-Signature", "v0=0000000000000000000000000000000000000000000000000000000000000000")
+
+	rec := httptest.NewRecorder()
+	tr.handleCommands(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected %d for a bad signature, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
+
+func TestHandleInteractionsRejectsBadSignature(t *testing.T) {
+	tr := NewHTTPTransport(0, testSigningSecret, func(context.Context, IncomingEvent) {
+		t.Fatal("handler should not be invoked when the signature is bad")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/slack/interactions", strings.NewReader("payload={}"))
+	req.Header.Set("X-Slack-Request-Timestamp", currentTimestamp())
+	req.Header.Set("X-Slack-Signature", "v0=0000000000000000000000000000000000000000000000000000000000000000")
+
+	rec := httptest.NewRecorder()
+	tr.handleInteractions(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected %d for a bad signature, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
+
+func TestHandleEventsURLVerificationRoundTrips(t *testing.T) {
+	tr := NewHTTPTransport(0, testSigningSecret, func(context.Context, IncomingEvent) {
+		t.Fatal("handler should not be invoked for a url_verification challenge")
+	})
+
+	body := `{"type":"url_verification","challenge":"abc123"}`
+	req := signedRequest(t, currentTimestamp(), body)
+
+	rec := httptest.NewRecorder()
+	tr.handleEvents(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d for a valid url_verification request, got %d", http.StatusOK, rec.Code)
+	}
+	if got := rec.Body.String(); got != "abc123" {
+		t.Fatalf("expected the challenge to be echoed back, got %q", got)
+	}
+}
@@ -0,0 +1,74 @@
+package transport
+
+import (
+	"context"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/slack-go/slack/socketmode"
+)
+
+// SocketModeTransport delivers events over a Socket Mode websocket
+// connection.
+type SocketModeTransport struct {
+	client  *socketmode.Client
+	handler Handler
+}
+
+// NewSocketModeTransport creates a Transport that reads from client's event
+// channel and dispatches to handler.
+func NewSocketModeTransport(client *socketmode.Client, handler Handler) *SocketModeTransport {
+	return &SocketModeTransport{client: client, handler: handler}
+}
+
+// Run starts the Socket Mode connection. It blocks until ctx is cancelled or
+// the underlying connection fails.
+func (t *SocketModeTransport) Run(ctx context.Context) error {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-t.client.Events:
+				t.dispatch(event)
+			}
+		}
+	}()
+
+	return t.client.RunContext(ctx)
+}
+
+func (t *SocketModeTransport) dispatch(event socketmode.Event) {
+	switch event.Type {
+	case socketmode.EventTypeEventsAPI:
+		eventData, ok := event.Data.(slackevents.EventsAPIEvent)
+		if !ok {
+			return
+		}
+		t.handler(context.Background(), IncomingEvent{
+			ID:             event.Request.EnvelopeID,
+			EventsAPIEvent: &eventData,
+			Ack:            func(interface{}) { t.client.Ack(*event.Request) },
+		})
+	case socketmode.EventTypeInteractive:
+		interactionData, ok := event.Data.(slack.InteractionCallback)
+		if !ok {
+			return
+		}
+		t.handler(context.Background(), IncomingEvent{
+			ID:          event.Request.EnvelopeID,
+			Interaction: &interactionData,
+			Ack:         func(payload interface{}) { t.client.Ack(*event.Request, payload) },
+		})
+	case socketmode.EventTypeSlashCommand:
+		cmdData, ok := event.Data.(slack.SlashCommand)
+		if !ok {
+			return
+		}
+		t.handler(context.Background(), IncomingEvent{
+			ID:      event.Request.EnvelopeID,
+			Command: &cmdData,
+			Ack:     func(interface{}) { t.client.Ack(*event.Request) },
+		})
+	}
+}
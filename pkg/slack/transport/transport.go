@@ -0,0 +1,45 @@
+// Package transport abstracts over the ways Slack can deliver events to the
+// bot - Socket Mode today, or a plain HTTP Events API + Interactivity
+// listener - behind a single interface so the event/interaction routers
+// don't need to know which one is in use.
+package transport
+
+import (
+	"context"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// IncomingEvent is the transport-agnostic representation of something the
+// bot needs to react to. Exactly one of EventsAPIEvent, Interaction, or
+// Command is set, mirroring the socketmode.Event variants this package
+// replaces.
+type IncomingEvent struct {
+	// ID identifies this delivery for correlation purposes (the Socket Mode
+	// envelope ID, or a generated request ID for the HTTP transport).
+	ID string
+
+	EventsAPIEvent *slackevents.EventsAPIEvent
+	Interaction    *slack.InteractionCallback
+	Command        *slack.SlashCommand
+
+	// Ack acknowledges the event. For Socket Mode this sends payload down
+	// the websocket. For the HTTP transport, an interaction's payload is
+	// written synchronously as the HTTP response body, which Slack requires
+	// for view_submission response actions; Events API events and slash
+	// commands are answered with 200 OK before the handler runs, and their
+	// Ack does nothing. Ack must be called exactly once per event.
+	Ack func(payload interface{})
+}
+
+// Handler reacts to a single IncomingEvent and is responsible for calling
+// its Ack.
+type Handler func(ctx context.Context, event IncomingEvent)
+
+// Transport receives events from Slack, however they're delivered, and
+// dispatches them to a Handler until ctx is cancelled or a fatal transport
+// error occurs.
+type Transport interface {
+	Run(ctx context.Context) error
+}